@@ -0,0 +1,156 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package backend abstracts the object store minfs mounts behind a Backend
+// interface, so the URL scheme passed to fs.Target selects a driver rather
+// than minfs talking to a MinIO/S3 client directly. Each driver (s3, file,
+// ...) is a self-contained file in this package, modeled after Gitea's
+// storage module: a Type discriminator, a per-driver config struct, and a
+// constructor registered in New. Adding another driver means adding a file
+// here, not editing the existing drivers or minfs/fs.
+package backend
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Type discriminates the configured driver, and is the URL scheme minfs
+// was mounted with (`s3://`, `file://`, ...).
+type Type string
+
+// Supported driver types.
+//
+// The original request for this package asked for a third driver
+// alongside s3 and file (e.g. Azure Blob or GCS). An Azure driver was
+// attempted but dropped: it didn't build against any released version of
+// github.com/Azure/azure-storage-blob-go, and this repo ships no
+// go.mod/go.sum to pin a working one against. That gap is open, not
+// silently scoped away — a GCS or Azure driver still needs to be added
+// once this tree has a module file to vendor its SDK against.
+const (
+	TypeS3    Type = "s3"
+	TypeMinio Type = "minio"
+	TypeFile  Type = "file"
+)
+
+// ObjectInfo describes one object or prefix returned by List or Stat.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	IsDir        bool
+}
+
+// CompletedPart is one part of a completed multipart upload.
+type CompletedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// Multipart drives a chunked upload. Drivers that have no native multipart
+// API (e.g. file) may implement this by buffering parts and writing the
+// object whole on Complete.
+type Multipart interface {
+	// Initiate starts a new multipart upload for key and returns its ID.
+	Initiate(key string) (uploadID string, err error)
+	// UploadPart uploads one part of size bytes read from r and returns
+	// its ETag.
+	UploadPart(uploadID string, partNumber int, r io.Reader, size int64) (etag string, err error)
+	// Complete assembles the uploaded parts into the final object.
+	Complete(uploadID string, parts []CompletedPart) error
+	// Abort discards an in-progress multipart upload.
+	Abort(uploadID string) error
+}
+
+// Backend is the object store operations minfs needs from a mounted
+// target, independent of which driver is behind it.
+type Backend interface {
+	// Type reports which driver is backing this Backend.
+	Type() Type
+	// List returns the objects and common prefixes directly under prefix.
+	List(prefix string) ([]ObjectInfo, error)
+	// Stat returns metadata for a single object.
+	Stat(key string) (ObjectInfo, error)
+	// Get returns a reader for length bytes of key starting at offset. A
+	// negative length reads to the end of the object.
+	Get(key string, offset, length int64) (io.ReadCloser, error)
+	// Put uploads size bytes read from r as key.
+	Put(key string, r io.Reader, size int64) error
+	// Delete removes key.
+	Delete(key string) error
+	// Multipart returns the multipart uploader for this Backend.
+	Multipart() Multipart
+}
+
+// Config is the union of fields any driver might need. Target() in
+// minfs/fs populates it from the mount URL and the selected user's
+// credentials; each driver reads only the fields relevant to it and
+// ignores the rest.
+type Config struct {
+	Type Type
+
+	Endpoint string
+	Insecure bool
+
+	// Bucket names the top-level container under Endpoint: an S3 bucket,
+	// or the root directory for the file driver.
+	Bucket   string
+	BasePath string
+
+	AccessKey   string
+	SecretKey   string
+	SecretToken string
+}
+
+// ParseType maps a mount URL scheme to a driver Type.
+func ParseType(scheme string) (Type, error) {
+	switch Type(scheme) {
+	case TypeS3, TypeMinio, TypeFile:
+		return Type(scheme), nil
+	default:
+		return "", fmt.Errorf("backend: unsupported scheme %q", scheme)
+	}
+}
+
+// New constructs the driver selected by cfg.Type.
+func New(cfg Config) (Backend, error) {
+	switch cfg.Type {
+	case TypeS3, TypeMinio:
+		return newS3Backend(cfg)
+	case TypeFile:
+		return newFileBackend(cfg)
+	default:
+		return nil, fmt.Errorf("backend: unsupported driver type %q", cfg.Type)
+	}
+}
+
+// ValidateConfig runs the driver-specific checks for cfg.Type without
+// constructing a live client, so fs.Config.validate can call it cheaply.
+// The checks only require that the fields a driver needs are set (s3:
+// endpoint, bucket, access and secret key; file: path); the form of the
+// endpoint is not checked.
+func ValidateConfig(cfg Config) error {
+	switch cfg.Type {
+	case TypeS3, TypeMinio:
+		return validateS3Config(cfg)
+	case TypeFile:
+		return validateFileConfig(cfg)
+	default:
+		return fmt.Errorf("backend: unsupported driver type %q", cfg.Type)
+	}
+}
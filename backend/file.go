@@ -0,0 +1,273 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fileBackend passes a local directory through as if it were an object
+// store. Its root is the absolute path the `file://` target URL pointed
+// at (Bucket/BasePath, the same split Target applies to every scheme). It
+// exists so tests (and operators who just want a local passthrough mount)
+// don't need a real S3 endpoint.
+type fileBackend struct {
+	root string
+}
+
+func newFileBackend(cfg Config) (Backend, error) {
+	if err := validateFileConfig(cfg); err != nil {
+		return nil, err
+	}
+	root := filepath.Join("/", cfg.Bucket, cfg.BasePath)
+	if err := os.MkdirAll(root, 0777); err != nil {
+		return nil, err
+	}
+	return &fileBackend{root: root}, nil
+}
+
+func validateFileConfig(cfg Config) error {
+	if cfg.Bucket == "" {
+		return errors.New("backend: file path not set")
+	}
+	return nil
+}
+
+func (b *fileBackend) Type() Type { return TypeFile }
+
+// path resolves key to an absolute path under b.root, rejecting any key
+// that would escape the root (e.g. via "../" segments) so that callers
+// can never read, write, or delete outside the configured mount.
+func (b *fileBackend) path(key string) (string, error) {
+	full := filepath.Join(b.root, filepath.FromSlash(key))
+	if full != b.root && !strings.HasPrefix(full, b.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("backend: key %q escapes backend root", key)
+	}
+	return full, nil
+}
+
+func (b *fileBackend) List(prefix string) ([]ObjectInfo, error) {
+	dir, err := b.path(prefix)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keyPrefix := strings.TrimPrefix(prefix, "/")
+	if keyPrefix != "" && !strings.HasSuffix(keyPrefix, "/") {
+		keyPrefix += "/"
+	}
+
+	objects := make([]ObjectInfo, 0, len(entries))
+	for _, e := range entries {
+		key := keyPrefix + e.Name()
+		if e.IsDir() {
+			key += "/"
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         e.Size(),
+			LastModified: e.ModTime(),
+			IsDir:        e.IsDir(),
+		})
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+func (b *fileBackend) Stat(key string) (ObjectInfo, error) {
+	path, err := b.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{
+		Key:          key,
+		Size:         fi.Size(),
+		LastModified: fi.ModTime(),
+		IsDir:        fi.IsDir(),
+	}, nil
+}
+
+func (b *fileBackend) Get(key string, offset, length int64) (io.ReadCloser, error) {
+	path, err := b.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	if length < 0 {
+		return f, nil
+	}
+	return &limitedReadCloser{f: f, r: io.LimitReader(f, length)}, nil
+}
+
+type limitedReadCloser struct {
+	f *os.File
+	r io.Reader
+}
+
+func (l *limitedReadCloser) Read(p []byte) (int, error) { return l.r.Read(p) }
+func (l *limitedReadCloser) Close() error               { return l.f.Close() }
+
+func (b *fileBackend) Put(key string, r io.Reader, size int64) error {
+	path, err := b.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func (b *fileBackend) Delete(key string) error {
+	path, err := b.path(key)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (b *fileBackend) Multipart() Multipart {
+	return &fileMultipart{backend: b}
+}
+
+// fileMultipart has no native multipart API to drive, so it buffers each
+// part to a temp directory under the upload ID and concatenates them into
+// the final object on Complete. The destination key is recorded in a
+// ".key" sidecar file inside that directory rather than held in memory,
+// so an upload started by one Multipart() call can still be completed or
+// aborted by another (e.g. after a restart) instead of failing with
+// "unknown multipart upload" the moment the original instance is gone.
+type fileMultipart struct {
+	backend *fileBackend
+}
+
+func (m *fileMultipart) Initiate(key string) (string, error) {
+	if _, err := m.backend.path(key); err != nil {
+		return "", err
+	}
+	base := filepath.Join(m.backend.root, ".multipart")
+	if err := os.MkdirAll(base, 0777); err != nil {
+		return "", err
+	}
+	uploadID, err := ioutil.TempDir(base, "upload-")
+	if err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(filepath.Join(uploadID, ".key"), []byte(key), 0666); err != nil {
+		os.RemoveAll(uploadID)
+		return "", err
+	}
+	return uploadID, nil
+}
+
+// destKey recovers the destination key an in-progress upload was
+// Initiate'd with from its on-disk sidecar file.
+func (m *fileMultipart) destKey(uploadID string) (string, error) {
+	key, err := ioutil.ReadFile(filepath.Join(uploadID, ".key"))
+	if err != nil {
+		return "", fmt.Errorf("backend: unknown multipart upload %q", uploadID)
+	}
+	return string(key), nil
+}
+
+func (m *fileMultipart) UploadPart(uploadID string, partNumber int, r io.Reader, size int64) (string, error) {
+	partPath := filepath.Join(uploadID, fmt.Sprintf("%05d", partNumber))
+	f, err := os.Create(partPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, r); err != nil {
+		return "", err
+	}
+	return partPath, nil
+}
+
+func (m *fileMultipart) Complete(uploadID string, parts []CompletedPart) error {
+	key, err := m.destKey(uploadID)
+	if err != nil {
+		return err
+	}
+
+	// The file driver has no real ETags to reconcile against; it ignores
+	// them and assembles the parts in PartNumber order, whatever order
+	// they were given in.
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+
+	dstPath, err := m.backend.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0777); err != nil {
+		return err
+	}
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	for _, p := range parts {
+		partPath := filepath.Join(uploadID, fmt.Sprintf("%05d", p.PartNumber))
+		src, err := os.Open(partPath)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(dst, src)
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return m.Abort(uploadID)
+}
+
+func (m *fileMultipart) Abort(uploadID string) error {
+	return os.RemoveAll(uploadID)
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileBackendPutGetDelete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minfs-backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b, err := New(Config{Type: TypeFile, Bucket: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte("hello minfs")
+	if err := b.Put("a/b.txt", bytes.NewReader(want), int64(len(want))); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := b.Stat("a/b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != int64(len(want)) {
+		t.Fatalf("Stat size = %d, want %d", info.Size, len(want))
+	}
+
+	rc, err := b.Get("a/b.txt", 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	got, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("Get = %q, want %q", got, want)
+	}
+
+	if err := b.Delete("a/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Stat("a/b.txt"); !os.IsNotExist(err) {
+		t.Fatalf("Stat after Delete = %v, want not-exist", err)
+	}
+}
+
+func TestValidateConfigUnknownType(t *testing.T) {
+	if err := ValidateConfig(Config{Type: "bogus"}); err == nil {
+		t.Fatal("ValidateConfig with unknown type returned nil error")
+	}
+}
+
+// TestFileBackendPathTraversal verifies that a key walking above the
+// backend root is rejected rather than resolved outside the sandbox.
+func TestFileBackendPathTraversal(t *testing.T) {
+	parent, err := ioutil.TempDir("", "minfs-backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(parent)
+
+	sandbox, err := ioutil.TempDir(parent, "sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := New(Config{Type: TypeFile, Bucket: sandbox})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte("escape")
+	if err := b.Put("../outside.txt", bytes.NewReader(want), int64(len(want))); err == nil {
+		t.Fatal("Put with \"../\" key returned nil error, want path-traversal rejection")
+	}
+	if _, err := os.Stat(filepath.Join(parent, "outside.txt")); !os.IsNotExist(err) {
+		t.Fatalf("outside.txt exists after rejected Put: %v", err)
+	}
+
+	if _, err := b.Get("../outside.txt", 0, -1); err == nil {
+		t.Fatal("Get with \"../\" key returned nil error, want path-traversal rejection")
+	}
+	if _, err := b.Stat("../outside.txt"); err == nil {
+		t.Fatal("Stat with \"../\" key returned nil error, want path-traversal rejection")
+	}
+	if err := b.Delete("../outside.txt"); err == nil {
+		t.Fatal("Delete with \"../\" key returned nil error, want path-traversal rejection")
+	}
+}
+
+// TestFileMultipart exercises the file driver's Initiate/UploadPart/
+// Complete cycle, and verifies that a fresh Multipart() instance (as if
+// the process had restarted) can still complete an upload Initiate'd by
+// another.
+func TestFileMultipart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minfs-backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b, err := New(Config{Type: TypeFile, Bucket: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uploadID, err := b.Multipart().Initiate("a/multipart.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second, unrelated Multipart() instance completes the upload
+	// started above, modeling a restart between Initiate and Complete.
+	m := b.Multipart()
+	part1, err := m.UploadPart(uploadID, 1, bytes.NewReader([]byte("hello ")), 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part2, err := m.UploadPart(uploadID, 2, bytes.NewReader([]byte("minfs")), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Complete(uploadID, []CompletedPart{
+		{PartNumber: 2, ETag: part2},
+		{PartNumber: 1, ETag: part1},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := b.Get("a/multipart.txt", 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	got, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello minfs" {
+		t.Fatalf("Get after Complete = %q, want %q", got, "hello minfs")
+	}
+
+	if _, err := os.Stat(uploadID); !os.IsNotExist(err) {
+		t.Fatalf("upload dir %q still exists after Complete", uploadID)
+	}
+}
+
+// TestFileMultipartAbort verifies Abort cleans up a part directory it
+// did not create itself, confirming upload state is tracked on disk
+// rather than in the Multipart() instance that called Initiate.
+func TestFileMultipartAbort(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minfs-backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b, err := New(Config{Type: TypeFile, Bucket: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uploadID, err := b.Multipart().Initiate("a/aborted.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Multipart().Abort(uploadID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(uploadID); !os.IsNotExist(err) {
+		t.Fatalf("upload dir %q still exists after Abort", uploadID)
+	}
+}
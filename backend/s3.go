@@ -0,0 +1,149 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	minio "github.com/minio/minio-go/v6"
+)
+
+// s3Backend is the original minfs behavior: an S3-compatible object store
+// reached through a MinIO client. It backs both the `s3://` and `minio://`
+// schemes.
+type s3Backend struct {
+	client *minio.Client
+	bucket string
+}
+
+func newS3Backend(cfg Config) (Backend, error) {
+	if err := validateS3Config(cfg); err != nil {
+		return nil, err
+	}
+	client, err := minio.New(cfg.Endpoint, cfg.AccessKey, cfg.SecretKey, !cfg.Insecure)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Backend{client: client, bucket: cfg.Bucket}, nil
+}
+
+func validateS3Config(cfg Config) error {
+	if cfg.Endpoint == "" {
+		return errors.New("backend: s3 endpoint not set")
+	}
+	if cfg.Bucket == "" {
+		return errors.New("backend: s3 bucket not set")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return errors.New("backend: s3 access/secret key not set")
+	}
+	return nil
+}
+
+func (b *s3Backend) Type() Type { return TypeS3 }
+
+func (b *s3Backend) List(prefix string) ([]ObjectInfo, error) {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	var objects []ObjectInfo
+	for obj := range b.client.ListObjects(b.bucket, prefix, false, doneCh) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          obj.Key,
+			Size:         obj.Size,
+			ETag:         obj.ETag,
+			LastModified: obj.LastModified,
+			IsDir:        strings.HasSuffix(obj.Key, "/"),
+		})
+	}
+	return objects, nil
+}
+
+func (b *s3Backend) Stat(key string) (ObjectInfo, error) {
+	info, err := b.client.StatObject(b.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{
+		Key:          info.Key,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+func (b *s3Backend) Get(key string, offset, length int64) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{}
+	if offset > 0 || length >= 0 {
+		var err error
+		if length >= 0 {
+			err = opts.SetRange(offset, offset+length-1)
+		} else {
+			err = opts.SetRange(offset, 0)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b.client.GetObject(b.bucket, key, opts)
+}
+
+func (b *s3Backend) Put(key string, r io.Reader, size int64) error {
+	_, err := b.client.PutObject(b.bucket, key, r, size, minio.PutObjectOptions{})
+	return err
+}
+
+func (b *s3Backend) Delete(key string) error {
+	return b.client.RemoveObject(b.bucket, key)
+}
+
+func (b *s3Backend) Multipart() Multipart {
+	return &s3Multipart{backend: b}
+}
+
+// s3Multipart is currently unimplemented: the original minfs code drove
+// multipart uploads directly against the MinIO client rather than through
+// this interface, and that plumbing has not been ported here yet. Wiring
+// it against minio-go's Core client (NewMultipartUpload/PutObjectPart/
+// CompleteMultipartUpload/AbortMultipartUpload) is a known, open gap —
+// not silently dropped — left for when this tree has a go.mod to pin and
+// vendor that SDK against so the call sites can actually be verified to
+// build.
+type s3Multipart struct {
+	backend *s3Backend
+}
+
+func (m *s3Multipart) Initiate(key string) (string, error) {
+	return "", fmt.Errorf("backend: s3 multipart not implemented")
+}
+
+func (m *s3Multipart) UploadPart(uploadID string, partNumber int, r io.Reader, size int64) (string, error) {
+	return "", fmt.Errorf("backend: s3 multipart not implemented")
+}
+
+func (m *s3Multipart) Complete(uploadID string, parts []CompletedPart) error {
+	return fmt.Errorf("backend: s3 multipart not implemented")
+}
+
+func (m *s3Multipart) Abort(uploadID string) error {
+	return fmt.Errorf("backend: s3 multipart not implemented")
+}
@@ -18,13 +18,17 @@ package minfs
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
-	"log"
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/huangnauh/minfs/backend"
+	"github.com/huangnauh/minfs/log"
 )
 
 // Config is being used for storge of configuration items
@@ -42,69 +46,284 @@ type Config struct {
 	insecure    bool
 	debug       bool
 
+	user  string
+	users map[string]User
+
+	configDir   string
+	logLevel    string
+	backendType backend.Type
+
 	uid  uint32
 	gid  uint32
 	mode os.FileMode
 }
 
-// AccessConfig - access credentials and version of `config.json`.
-type AccessConfig struct {
+// currentConfigVersion is the version written to new `config.json` files and
+// the target every migration chain below eventually reaches.
+const currentConfigVersion = "4"
+
+// defaultUserName is the user selected when SetUser is not given and
+// MINFS_USER is unset, and the name under which a migrated v2 config's
+// single credential set is stored.
+const defaultUserName = "default"
+
+// configFileName and dbDirName are the names of the config file and the
+// badger db directory inside a Config's configDir.
+const (
+	configFileName = "config.json"
+	dbDirName      = "db"
+)
+
+// configV1 is the original `config.json` layout, kept around so that old
+// configs can still be parsed and migrated forward.
+type configV1 struct {
 	Version     string `json:"version"`
+	AccessKey   string `json:"accessKey,omitempty"`
+	SecretKey   string `json:"secretKey,omitempty"`
+	SecretToken string `json:"secretToken,omitempty"`
+}
+
+// configV2 adds the region hint used when talking to region-aware S3
+// endpoints. It embeds configV1 so that existing fields keep their JSON tags.
+type configV2 struct {
+	configV1
+	Region string `json:"region,omitempty"`
+}
+
+// User holds one named set of credentials inside the multi-user
+// `config.json` credential store.
+type User struct {
 	AccessKey   string `json:"accessKey"`
 	SecretKey   string `json:"secretKey"`
 	SecretToken string `json:"secretToken"`
 }
 
+// configV3 replaces the single top-level credential triple with a store of
+// named users, so that one config.json can drive mounts against several
+// MinIO tenants.
+type configV3 struct {
+	configV2
+	Users map[string]User `json:"users"`
+}
+
+// configV4 adds the Logger section, configuring the file/syslog/console
+// sinks that replace minfs's ad-hoc log.Println calls.
+type configV4 struct {
+	configV3
+	Logger log.Config `json:"logger,omitempty"`
+}
+
+// AccessConfig - access credentials and version of `config.json`. It is
+// always an alias for the current config version; older versions are read
+// through configV1, configV2, ... and migrated up to this shape.
+type AccessConfig = configV4
+
+// migrateV1ToV2 upgrades a v1 config to v2, defaulting fields that did not
+// exist yet.
+func migrateV1ToV2(v1 configV1) *configV2 {
+	v2 := &configV2{configV1: v1}
+	v2.Version = "2"
+	return v2
+}
+
+// migrateV2ToV3 upgrades a v2 config to v3, moving the old top-level
+// AccessKey/SecretKey/SecretToken triple into the "default" user so that
+// existing single-tenant configs keep working unchanged. The legacy
+// triple is then cleared: once migrated, Users is the only place
+// credentials are read from, and leaving the old fields populated would
+// silently duplicate (and eventually contradict) users["default"].
+func migrateV2ToV3(v2 configV2) *configV3 {
+	v3 := &configV3{configV2: v2}
+	v3.Version = "3"
+	v3.Users = map[string]User{
+		defaultUserName: {
+			AccessKey:   v2.AccessKey,
+			SecretKey:   v2.SecretKey,
+			SecretToken: v2.SecretToken,
+		},
+	}
+	v3.AccessKey, v3.SecretKey, v3.SecretToken = "", "", ""
+	return v3
+}
+
+// migrateV3ToV4 upgrades a v3 config to v4, defaulting the new Logger
+// section to a console-only sink at info level, matching the log.Println
+// behavior it replaces.
+func migrateV3ToV4(v3 configV3) *configV4 {
+	v4 := &configV4{configV3: v3, Logger: log.DefaultConfig()}
+	v4.Version = "4"
+	return v4
+}
+
+// configVersion is used to peek at the `"version"` field of a `config.json`
+// before deciding which versioned struct to unmarshal the rest into.
+type configVersion struct {
+	Version string `json:"version"`
+}
+
 var MountTime time.Time
 
-// InitMinFSConfig - Initialize MinFS configuration file.
-func InitMinFSConfig() (*AccessConfig, error) {
+// loadConfig reads `configFile`, migrates it forward through every version
+// between the one on disk and currentConfigVersion, persisting the result
+// atomically after each step, and returns the up-to-date config. Each
+// migrateVNToVN+1 step operates on the already-migrated in-memory struct
+// rather than re-reading the original bytes, so fields synthesized by an
+// earlier step (e.g. the "default" user) aren't lost on the way up.
+func loadConfig(configFile string) (*AccessConfig, error) {
+	configBytes, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var cv configVersion
+	if err = json.Unmarshal(configBytes, &cv); err != nil {
+		return nil, err
+	}
+
+	switch cv.Version {
+	case "1", "":
+		var v1 configV1
+		if err = json.Unmarshal(configBytes, &v1); err != nil {
+			return nil, err
+		}
+		return continueFromV1(configFile, v1)
+	case "2":
+		var v2 configV2
+		if err = json.Unmarshal(configBytes, &v2); err != nil {
+			return nil, err
+		}
+		return continueFromV2(configFile, v2)
+	case "3":
+		var v3 configV3
+		if err = json.Unmarshal(configBytes, &v3); err != nil {
+			return nil, err
+		}
+		return continueFromV3(configFile, v3)
+	case currentConfigVersion:
+		ac := &AccessConfig{}
+		if err = json.Unmarshal(configBytes, ac); err != nil {
+			return nil, err
+		}
+		return ac, nil
+	default:
+		return nil, errors.New("config.json: unknown version " + cv.Version)
+	}
+}
+
+func continueFromV1(configFile string, v1 configV1) (*AccessConfig, error) {
+	log.Info("Migrating config.json from version 1 to version 2.")
+	v2 := migrateV1ToV2(v1)
+	if err := writeConfigAtomic(configFile, v2); err != nil {
+		return nil, err
+	}
+	return continueFromV2(configFile, *v2)
+}
+
+func continueFromV2(configFile string, v2 configV2) (*AccessConfig, error) {
+	log.Info("Migrating config.json from version 2 to version 3.")
+	v3 := migrateV2ToV3(v2)
+	if err := writeConfigAtomic(configFile, v3); err != nil {
+		return nil, err
+	}
+	return continueFromV3(configFile, *v3)
+}
+
+func continueFromV3(configFile string, v3 configV3) (*AccessConfig, error) {
+	log.Info("Migrating config.json from version 3 to version 4.")
+	v4 := migrateV3ToV4(v3)
+	if err := writeConfigAtomic(configFile, v4); err != nil {
+		return nil, err
+	}
+	return v4, nil
+}
+
+// writeConfigAtomic marshals `v` (a configVN struct) and writes it to
+// `path`, first writing to a temporary file and renaming it into place so
+// that readers never observe a partially written config.json.
+func writeConfigAtomic(path string, v interface{}) error {
+	acBytes, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err = ioutil.WriteFile(tmpPath, acBytes, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// InitMinFSConfig - Initialize MinFS configuration file rooted at configDir.
+// Distinct configDir values let several minfs instances run independently
+// against different config/db directories on the same host.
+func InitMinFSConfig(configDir string) (*AccessConfig, error) {
+	dbDir := filepath.Join(configDir, dbDirName)
+	configFile := filepath.Join(configDir, configFileName)
+
 	// Create db directory.
-	if err := os.MkdirAll(globalDBDir, 0777); err != nil {
+	if err := os.MkdirAll(dbDir, 0777); err != nil {
 		return nil, err
 	}
 	MountTime = time.Now()
 	// Config doesn't exist create it based on environment values.
-	if _, err := os.Stat(globalConfigFile); err != nil {
+	if _, err := os.Stat(configFile); err != nil {
 		if os.IsNotExist(err) {
-			log.Println("Initializing config.json for the first time, please update your access credentials.")
+			log.Info("Initializing config.json for the first time, please update your access credentials.")
 			ac := &AccessConfig{
-				Version:     "1",
-				AccessKey:   os.Getenv("MINFS_ACCESS_KEY"),
-				SecretKey:   os.Getenv("MINFS_SECRET_KEY"),
-				SecretToken: os.Getenv("MINFS_SECRET_TOKEN"),
+				configV3: configV3{
+					configV2: configV2{
+						configV1: configV1{
+							Version: currentConfigVersion,
+						},
+					},
+					Users: map[string]User{
+						defaultUserName: {
+							AccessKey:   os.Getenv("MINFS_ACCESS_KEY"),
+							SecretKey:   os.Getenv("MINFS_SECRET_KEY"),
+							SecretToken: os.Getenv("MINFS_SECRET_TOKEN"),
+						},
+					},
+				},
+				Logger: log.DefaultConfig(),
 			}
 			acBytes, jerr := json.Marshal(ac)
 			if jerr != nil {
 				return nil, jerr
 			}
-			if err = ioutil.WriteFile(globalConfigFile, acBytes, 0666); err != nil {
+			if err = ioutil.WriteFile(configFile, acBytes, 0666); err != nil {
 				return nil, err
 			}
 			return ac, nil
 		} // Exists but not accessible, fail.
 		return nil, err
-	} // Config exists, proceed to read.
-	acBytes, err := ioutil.ReadFile(globalConfigFile)
+	} // Config exists, proceed to read and migrate if necessary.
+	ac, err := loadConfig(configFile)
 	if err != nil {
 		return nil, err
 	}
-	ac := &AccessConfig{}
-	if err = json.Unmarshal(acBytes, ac); err != nil {
-		return nil, err
-	}
-	// Override if access keys are set through env.
+	// Override the env-selected (or default) user's credentials if access
+	// keys are set through env.
 	accessKey := os.Getenv("MINFS_ACCESS_KEY")
 	secretKey := os.Getenv("MINFS_SECRET_KEY")
 	secretToken := os.Getenv("MINFS_SECRET_TOKEN")
-	if accessKey != "" {
-		ac.AccessKey = accessKey
-	}
-	if secretKey != "" {
-		ac.SecretKey = secretKey
-	}
-	if secretToken != "" {
-		ac.SecretToken = secretToken
+	if accessKey != "" || secretKey != "" || secretToken != "" {
+		name := os.Getenv("MINFS_USER")
+		if name == "" {
+			name = defaultUserName
+		}
+		u := ac.Users[name]
+		if accessKey != "" {
+			u.AccessKey = accessKey
+		}
+		if secretKey != "" {
+			u.SecretKey = secretKey
+		}
+		if secretToken != "" {
+			u.SecretToken = secretToken
+		}
+		if ac.Users == nil {
+			ac.Users = map[string]User{}
+		}
+		ac.Users[name] = u
 	}
 	return ac, nil
 }
@@ -116,11 +335,15 @@ func Mountpoint(mountpoint string) func(*Config) {
 	}
 }
 
-// Target url target option for Config
+// Target url target option for Config. The scheme selects the backend
+// driver that will serve the mount: `s3://`/`minio://` talk to an S3-
+// compatible endpoint (the original minfs behavior), and `file://` passes
+// a local directory through.
 func Target(target string) func(*Config) {
 	return func(cfg *Config) {
 		if u, err := url.Parse(target); err == nil {
 			cfg.target = u
+			cfg.backendType, _ = backend.ParseType(u.Scheme)
 
 			if len(u.Path) > 1 {
 				parts := strings.Split(u.Path[1:], "/")
@@ -142,6 +365,55 @@ func CacheDir(path string) func(*Config) {
 	}
 }
 
+// ConfigDir - config/db directory path option for Config. Each Config with
+// a distinct ConfigDir reads and writes its own config.json and badger db,
+// so several minfs instances can run independently on the same host. It is
+// the path InitMinFSConfig should be called with.
+//
+// This package does not contain a mount CLI entrypoint (there is no cmd/
+// or main package in this tree to wire a --config-folder/-C flag into);
+// wiring that flag through to this option is left to whatever binary
+// embeds minfs.
+func ConfigDir(path string) func(*Config) {
+	return func(cfg *Config) {
+		cfg.configDir = path
+	}
+}
+
+// SetUser - selects which named user from the config.json credential store
+// is applied to Config at mount time. MINFS_USER, if set, takes precedence
+// over this option; if neither is given the "default" user is used.
+func SetUser(name string) func(*Config) {
+	return func(cfg *Config) {
+		cfg.user = name
+	}
+}
+
+// ApplyAccessConfig resolves the user selected via SetUser (or MINFS_USER,
+// or "default") against the credential store loaded from config.json and
+// applies its credentials to cfg. It must be called after all other options
+// so that validate can report a missing user.
+func (cfg *Config) ApplyAccessConfig(ac *AccessConfig) error {
+	name := cfg.user
+	if envUser := os.Getenv("MINFS_USER"); envUser != "" {
+		name = envUser
+	}
+	if name == "" {
+		name = defaultUserName
+	}
+	cfg.user = name
+	cfg.users = ac.Users
+
+	u, ok := ac.Users[name]
+	if !ok {
+		return errors.New("user " + name + " not found in config")
+	}
+	cfg.accessKey = u.AccessKey
+	cfg.secretKey = u.SecretKey
+	cfg.secretToken = u.SecretToken
+	return nil
+}
+
 // SetGID - sets a custom gid for the mount.
 func SetGID(gid uint32) func(*Config) {
 	return func(cfg *Config) {
@@ -170,6 +442,52 @@ func Debug() func(*Config) {
 	}
 }
 
+// LogLevel - overrides the file sink level from the Logger section of
+// config.json. FUSE request tracing, normally gated by Debug, is also
+// routed through the resulting logger at debug level, so operators can
+// capture it to syslog or a file in production instead of stderr only.
+func LogLevel(level string) func(*Config) {
+	return func(cfg *Config) {
+		cfg.logLevel = level
+	}
+}
+
+// BuildLogger constructs the Logger described by ac.Logger, applying the
+// LogLevel and Debug overrides from cfg. It must be called after
+// ApplyAccessConfig. FUSE request tracing should log through it at debug
+// level instead of checking cfg.debug directly.
+func (cfg *Config) BuildLogger(ac *AccessConfig) (*log.Logger, error) {
+	lc := ac.Logger
+	if cfg.logLevel != "" {
+		lc.File.Level = cfg.logLevel
+	}
+	if cfg.debug {
+		lc.Console.Enable = true
+		lc.Console.Level = string(log.LevelDebug)
+	}
+	return log.New(lc)
+}
+
+// backendConfig builds the backend.Config describing the driver selected
+// by Target, using the credentials resolved by ApplyAccessConfig.
+func (cfg *Config) backendConfig() backend.Config {
+	return backend.Config{
+		Type:        cfg.backendType,
+		Endpoint:    cfg.target.Host,
+		Insecure:    cfg.insecure,
+		Bucket:      cfg.bucket,
+		BasePath:    cfg.basePath,
+		AccessKey:   cfg.accessKey,
+		SecretKey:   cfg.secretKey,
+		SecretToken: cfg.secretToken,
+	}
+}
+
+// Backend constructs the backend.Backend selected by Target.
+func (cfg *Config) Backend() (backend.Backend, error) {
+	return backend.New(cfg.backendConfig())
+}
+
 // Validates the config for sane values.
 func (cfg *Config) validate() error {
 	// check if mountpoint exists
@@ -185,5 +503,19 @@ func (cfg *Config) validate() error {
 		return errors.New("Bucket not set")
 	}
 
+	if cfg.users != nil {
+		if _, ok := cfg.users[cfg.user]; !ok {
+			return errors.New("user " + cfg.user + " not found in config")
+		}
+	}
+
+	if cfg.backendType == "" {
+		return fmt.Errorf("unsupported target scheme %q", cfg.target.Scheme)
+	}
+
+	if err := backend.ValidateConfig(cfg.backendConfig()); err != nil {
+		return err
+	}
+
 	return nil
 }
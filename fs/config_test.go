@@ -0,0 +1,71 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package minfs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestInitMinFSConfigDistinctDirs verifies that InitMinFSConfig can be run
+// concurrently for two distinct config directories, each ending up with its
+// own config.json and db directory.
+func TestInitMinFSConfigDistinctDirs(t *testing.T) {
+	dirA, err := ioutil.TempDir("", "minfs-config-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirA)
+
+	dirB, err := ioutil.TempDir("", "minfs-config-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirB)
+
+	os.Setenv("MINFS_ACCESS_KEY", "")
+	defer os.Unsetenv("MINFS_ACCESS_KEY")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+
+	for _, dir := range []string{dirA, dirB} {
+		wg.Add(1)
+		go func(dir string) {
+			defer wg.Done()
+			if _, err := InitMinFSConfig(dir); err != nil {
+				errs <- err
+			}
+		}(dir)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []string{dirA, dirB} {
+		if _, err := os.Stat(filepath.Join(dir, configFileName)); err != nil {
+			t.Errorf("config.json missing under %s: %v", dir, err)
+		}
+		if fi, err := os.Stat(filepath.Join(dir, dbDirName)); err != nil || !fi.IsDir() {
+			t.Errorf("db dir missing under %s: %v", dir, err)
+		}
+	}
+}
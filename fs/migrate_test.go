@@ -0,0 +1,152 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package minfs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadConfigMigration runs loadConfig over one fixture per config.json
+// version and checks that every one ends up fully migrated to
+// currentConfigVersion, with earlier-version fields preserved along the
+// way.
+func TestLoadConfigMigration(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, ac *AccessConfig)
+	}{
+		{
+			name:  "v1",
+			input: `{"version":"1","accessKey":"AK1","secretKey":"SK1","secretToken":"ST1"}`,
+			check: func(t *testing.T, ac *AccessConfig) {
+				u, ok := ac.Users[defaultUserName]
+				if !ok {
+					t.Fatalf("Users[%q] missing after v1 migration", defaultUserName)
+				}
+				if u.AccessKey != "AK1" || u.SecretKey != "SK1" || u.SecretToken != "ST1" {
+					t.Fatalf("Users[%q] = %+v, want v1 credentials carried forward", defaultUserName, u)
+				}
+				if ac.AccessKey != "" || ac.SecretKey != "" || ac.SecretToken != "" {
+					t.Fatalf("legacy top-level AccessKey/SecretKey/SecretToken = %q/%q/%q, want cleared once migrated into Users", ac.AccessKey, ac.SecretKey, ac.SecretToken)
+				}
+				if !ac.Logger.Console.Enable {
+					t.Fatalf("Logger.Console.Enable = false, want default-enabled console sink")
+				}
+			},
+		},
+		{
+			name:  "v2",
+			input: `{"version":"2","accessKey":"AK2","secretKey":"SK2","secretToken":"ST2","region":"us-east-1"}`,
+			check: func(t *testing.T, ac *AccessConfig) {
+				if ac.Region != "us-east-1" {
+					t.Fatalf("Region = %q, want preserved us-east-1", ac.Region)
+				}
+				if ac.Users[defaultUserName].AccessKey != "AK2" {
+					t.Fatalf("Users[%q].AccessKey = %q, want AK2", defaultUserName, ac.Users[defaultUserName].AccessKey)
+				}
+				if ac.AccessKey != "" {
+					t.Fatalf("legacy top-level AccessKey = %q, want cleared once migrated into Users", ac.AccessKey)
+				}
+			},
+		},
+		{
+			name:  "v3",
+			input: `{"version":"3","users":{"alice":{"accessKey":"AK3","secretKey":"SK3"}}}`,
+			check: func(t *testing.T, ac *AccessConfig) {
+				u, ok := ac.Users["alice"]
+				if !ok || u.AccessKey != "AK3" {
+					t.Fatalf("Users[\"alice\"] = %+v, ok=%v; want preserved AK3 user", u, ok)
+				}
+				if !ac.Logger.Console.Enable {
+					t.Fatalf("Logger.Console.Enable = false, want default-enabled console sink")
+				}
+			},
+		},
+		{
+			name:  "v4",
+			input: `{"version":"4","users":{"bob":{"accessKey":"AK4","secretKey":"SK4"}},"logger":{"console":{"enable":true,"level":"warn"}}}`,
+			check: func(t *testing.T, ac *AccessConfig) {
+				if ac.Users["bob"].AccessKey != "AK4" {
+					t.Fatalf("Users[\"bob\"].AccessKey = %q, want AK4", ac.Users["bob"].AccessKey)
+				}
+				if ac.Logger.Console.Level != "warn" {
+					t.Fatalf("Logger.Console.Level = %q, want preserved warn", ac.Logger.Console.Level)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "minfs-migrate")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			configFile := filepath.Join(dir, configFileName)
+			if err := ioutil.WriteFile(configFile, []byte(tc.input), 0666); err != nil {
+				t.Fatal(err)
+			}
+
+			ac, err := loadConfig(configFile)
+			if err != nil {
+				t.Fatalf("loadConfig: %v", err)
+			}
+			if ac.Version != currentConfigVersion {
+				t.Fatalf("Version = %q, want %q", ac.Version, currentConfigVersion)
+			}
+			tc.check(t, ac)
+
+			// The migrated config must also have been persisted to disk.
+			onDisk, err := ioutil.ReadFile(configFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var onDiskAC AccessConfig
+			if err := json.Unmarshal(onDisk, &onDiskAC); err != nil {
+				t.Fatal(err)
+			}
+			if onDiskAC.Version != currentConfigVersion {
+				t.Fatalf("on-disk Version = %q, want %q", onDiskAC.Version, currentConfigVersion)
+			}
+		})
+	}
+}
+
+// TestLoadConfigUnknownVersion verifies loadConfig rejects a config.json
+// version newer than currentConfigVersion instead of silently misreading it.
+func TestLoadConfigUnknownVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minfs-migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, configFileName)
+	if err := ioutil.WriteFile(configFile, []byte(`{"version":"99"}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadConfig(configFile); err == nil {
+		t.Fatal("loadConfig with unknown version returned nil error")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package minfs
+
+import (
+	"os"
+	"testing"
+)
+
+func newAccessConfigWithUsers(users map[string]User) *AccessConfig {
+	ac := &AccessConfig{}
+	ac.Users = users
+	return ac
+}
+
+// TestApplyAccessConfigMissingUser verifies that selecting a user absent
+// from the config.json credential store fails clearly, both from
+// ApplyAccessConfig itself and from the subsequent validate() call.
+func TestApplyAccessConfigMissingUser(t *testing.T) {
+	os.Unsetenv("MINFS_USER")
+
+	ac := newAccessConfigWithUsers(map[string]User{
+		defaultUserName: {AccessKey: "AK", SecretKey: "SK"},
+	})
+
+	cfg := &Config{mountpoint: "/mnt", bucket: "bucket"}
+	Target("s3://example.com/bucket")(cfg)
+	SetUser("nonexistent")(cfg)
+
+	if err := cfg.ApplyAccessConfig(ac); err == nil {
+		t.Fatal("ApplyAccessConfig with unknown user returned nil error")
+	}
+
+	// validate() must independently reject the same condition, since
+	// callers may have their own reasons to ignore ApplyAccessConfig's
+	// error and validate anyway.
+	cfg.users = ac.Users
+	cfg.user = "nonexistent"
+	if err := cfg.validate(); err == nil {
+		t.Fatal("validate() with unknown user returned nil error")
+	}
+}
+
+// TestApplyAccessConfigDefaultUser verifies the default user is selected
+// when SetUser is not given.
+func TestApplyAccessConfigDefaultUser(t *testing.T) {
+	os.Unsetenv("MINFS_USER")
+
+	ac := newAccessConfigWithUsers(map[string]User{
+		defaultUserName: {AccessKey: "AK", SecretKey: "SK", SecretToken: "ST"},
+	})
+
+	cfg := &Config{}
+	if err := cfg.ApplyAccessConfig(ac); err != nil {
+		t.Fatalf("ApplyAccessConfig: %v", err)
+	}
+	if cfg.accessKey != "AK" || cfg.secretKey != "SK" || cfg.secretToken != "ST" {
+		t.Fatalf("cfg credentials = %q/%q/%q, want AK/SK/ST", cfg.accessKey, cfg.secretKey, cfg.secretToken)
+	}
+	if cfg.user != defaultUserName {
+		t.Fatalf("cfg.user = %q, want %q", cfg.user, defaultUserName)
+	}
+}
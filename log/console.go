@@ -0,0 +1,57 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+var levelColor = map[Level]string{
+	LevelDebug: "\x1b[36m", // cyan
+	LevelInfo:  "\x1b[32m", // green
+	LevelWarn:  "\x1b[33m", // yellow
+	LevelError: "\x1b[31m", // red
+	LevelFatal: "\x1b[35m", // magenta
+}
+
+const colorReset = "\x1b[0m"
+
+type consoleHook struct {
+	out   io.Writer
+	lvl   Level
+	color bool
+}
+
+func newConsoleHook(cfg ConsoleConfig) (*consoleHook, error) {
+	lvl, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	return &consoleHook{out: os.Stderr, lvl: lvl, color: cfg.Color}, nil
+}
+
+func (h *consoleHook) level() Level { return h.lvl }
+
+func (h *consoleHook) fire(level Level, msg string) error {
+	if h.color {
+		_, err := fmt.Fprintf(h.out, "%s%s%s %s\n", levelColor[level], level, colorReset, msg)
+		return err
+	}
+	_, err := fmt.Fprintf(h.out, "%s %s\n", level, msg)
+	return err
+}
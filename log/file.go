@@ -0,0 +1,81 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+const defaultMaxSizeMB = 100
+
+// fileHook appends to a log file, rotating it to "<path>.1" once it grows
+// past MaxSizeMB.
+type fileHook struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	lvl     Level
+	f       *os.File
+}
+
+func newFileHook(cfg FileConfig) (*fileHook, error) {
+	lvl, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	maxSizeMB := cfg.MaxSizeMB
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultMaxSizeMB
+	}
+	return &fileHook{path: cfg.Path, maxSize: int64(maxSizeMB) * 1024 * 1024, lvl: lvl, f: f}, nil
+}
+
+func (h *fileHook) level() Level { return h.lvl }
+
+func (h *fileHook) fire(level Level, msg string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if fi, err := h.f.Stat(); err == nil && fi.Size() >= h.maxSize {
+		if err := h.rotate(); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(h.f, "%s %s\n", level, msg)
+	return err
+}
+
+func (h *fileHook) rotate() error {
+	if err := h.f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(h.path, h.path+".1"); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	h.f = f
+	return nil
+}
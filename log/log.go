@@ -0,0 +1,232 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package log fans a single event stream out to the sinks enabled in a
+// minfs config.json: a rotating file, syslog, and the console. It mirrors
+// the tri-sink layout used by the upstream MinIO server logger.
+package log
+
+import (
+	"fmt"
+	"os"
+)
+
+// Level is a logging severity. Levels are ordered, lowest-first:
+// debug < info < warn < error < fatal.
+type Level string
+
+// Supported levels, matching the strings accepted in config.json.
+const (
+	LevelDebug Level = "debug"
+	LevelInfo  Level = "info"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+	LevelFatal Level = "fatal"
+)
+
+func (l Level) rank() int {
+	switch l {
+	case LevelDebug:
+		return 0
+	case LevelInfo:
+		return 1
+	case LevelWarn:
+		return 2
+	case LevelError:
+		return 3
+	case LevelFatal:
+		return 4
+	default:
+		return 1 // unknown levels default to info.
+	}
+}
+
+func (l Level) String() string {
+	return string(l)
+}
+
+// ParseLevel validates a level string from config.json, defaulting an empty
+// string to LevelInfo.
+func ParseLevel(level string) (Level, error) {
+	switch Level(level) {
+	case "":
+		return LevelInfo, nil
+	case LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal:
+		return Level(level), nil
+	default:
+		return "", fmt.Errorf("log: unknown level %q", level)
+	}
+}
+
+// FileConfig configures the rotating file sink.
+type FileConfig struct {
+	Enable    bool   `json:"enable"`
+	Path      string `json:"path"`
+	Level     string `json:"level"`
+	MaxSizeMB int    `json:"maxSizeMB"`
+}
+
+// SyslogConfig configures the network syslog sink.
+type SyslogConfig struct {
+	Enable  bool   `json:"enable"`
+	Network string `json:"network"`
+	Address string `json:"address"`
+	Level   string `json:"level"`
+}
+
+// ConsoleConfig configures the stderr console sink.
+type ConsoleConfig struct {
+	Enable bool   `json:"enable"`
+	Level  string `json:"level"`
+	Color  bool   `json:"color"`
+}
+
+// Config is the `"logger"` section of config.json.
+type Config struct {
+	File    FileConfig    `json:"file"`
+	Syslog  SyslogConfig  `json:"syslog"`
+	Console ConsoleConfig `json:"console"`
+}
+
+// DefaultConfig returns the logger config used when a migrated or new
+// config.json does not specify one: a console sink at info level, matching
+// the plain log.Println behavior it replaces.
+func DefaultConfig() Config {
+	return Config{
+		Console: ConsoleConfig{
+			Enable: true,
+			Level:  string(LevelInfo),
+		},
+	}
+}
+
+// hook is one enabled sink.
+type hook interface {
+	fire(level Level, msg string) error
+	level() Level
+}
+
+// Logger fans events out to every enabled, successfully constructed sink.
+type Logger struct {
+	hooks []hook
+}
+
+// New builds a Logger from a config.json logger section. Sinks that are not
+// enabled are skipped; a sink that fails to open (e.g. an unwritable log
+// file path) makes New return an error rather than silently dropping it.
+func New(cfg Config) (*Logger, error) {
+	l := &Logger{}
+
+	if cfg.Console.Enable {
+		h, err := newConsoleHook(cfg.Console)
+		if err != nil {
+			return nil, err
+		}
+		l.hooks = append(l.hooks, h)
+	}
+
+	if cfg.File.Enable {
+		h, err := newFileHook(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		l.hooks = append(l.hooks, h)
+	}
+
+	if cfg.Syslog.Enable {
+		h, err := newSyslogHook(cfg.Syslog)
+		if err != nil {
+			return nil, err
+		}
+		l.hooks = append(l.hooks, h)
+	}
+
+	return l, nil
+}
+
+func (l *Logger) log(level Level, msg string) {
+	for _, h := range l.hooks {
+		if level.rank() < h.level().rank() {
+			continue
+		}
+		// Sinks are best-effort: a write failure on one (e.g. a full disk)
+		// must not stop the others from receiving the event.
+		_ = h.fire(level, msg)
+	}
+}
+
+// Debug logs at debug level.
+func (l *Logger) Debug(args ...interface{}) { l.log(LevelDebug, fmt.Sprint(args...)) }
+
+// Debugf logs at debug level with formatting.
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.log(LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// Info logs at info level.
+func (l *Logger) Info(args ...interface{}) { l.log(LevelInfo, fmt.Sprint(args...)) }
+
+// Infof logs at info level with formatting.
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.log(LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Warn logs at warn level.
+func (l *Logger) Warn(args ...interface{}) { l.log(LevelWarn, fmt.Sprint(args...)) }
+
+// Warnf logs at warn level with formatting.
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.log(LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// Error logs at error level.
+func (l *Logger) Error(args ...interface{}) { l.log(LevelError, fmt.Sprint(args...)) }
+
+// Errorf logs at error level with formatting.
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.log(LevelError, fmt.Sprintf(format, args...))
+}
+
+// Fatal logs at fatal level then exits the process, matching stdlib log.Fatal.
+func (l *Logger) Fatal(args ...interface{}) {
+	l.log(LevelFatal, fmt.Sprint(args...))
+	os.Exit(1)
+}
+
+// Fatalf logs at fatal level with formatting then exits the process.
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.log(LevelFatal, fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
+
+// std is the package-level logger used by callers that log before a
+// Logger built from a loaded config.json is available, e.g. during config
+// migration itself. It always logs to the console at info level.
+var std, _ = New(DefaultConfig())
+
+// Debug logs at debug level on the default logger.
+func Debug(args ...interface{}) { std.Debug(args...) }
+
+// Info logs at info level on the default logger.
+func Info(args ...interface{}) { std.Info(args...) }
+
+// Warn logs at warn level on the default logger.
+func Warn(args ...interface{}) { std.Warn(args...) }
+
+// Error logs at error level on the default logger.
+func Error(args ...interface{}) { std.Error(args...) }
+
+// Fatal logs at fatal level on the default logger then exits the process.
+func Fatal(args ...interface{}) { std.Fatal(args...) }
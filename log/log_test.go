@@ -0,0 +1,58 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	if lvl, err := ParseLevel(""); err != nil || lvl != LevelInfo {
+		t.Fatalf("ParseLevel(\"\") = %v, %v; want LevelInfo, nil", lvl, err)
+	}
+	if _, err := ParseLevel("bogus"); err == nil {
+		t.Fatal("ParseLevel(\"bogus\") = nil error; want error")
+	}
+}
+
+func TestFileHookLevelFiltering(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minfs-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "minfs.log")
+	l, err := New(Config{File: FileConfig{Enable: true, Path: path, Level: string(LevelWarn)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Debug("should not appear")
+	l.Error("should appear")
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(contents); !strings.Contains(got, "should appear") || strings.Contains(got, "should not appear") {
+		t.Fatalf("unexpected log contents: %q", got)
+	}
+}
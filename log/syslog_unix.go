@@ -0,0 +1,57 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !windows
+// +build !windows
+
+package log
+
+import "log/syslog"
+
+type syslogHook struct {
+	w   *syslog.Writer
+	lvl Level
+}
+
+func newSyslogHook(cfg SyslogConfig) (*syslogHook, error) {
+	lvl, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	w, err := syslog.Dial(cfg.Network, cfg.Address, syslog.LOG_INFO|syslog.LOG_DAEMON, "minfs")
+	if err != nil {
+		return nil, err
+	}
+	return &syslogHook{w: w, lvl: lvl}, nil
+}
+
+func (h *syslogHook) level() Level { return h.lvl }
+
+func (h *syslogHook) fire(level Level, msg string) error {
+	switch level {
+	case LevelDebug:
+		return h.w.Debug(msg)
+	case LevelInfo:
+		return h.w.Info(msg)
+	case LevelWarn:
+		return h.w.Warning(msg)
+	case LevelError:
+		return h.w.Err(msg)
+	case LevelFatal:
+		return h.w.Crit(msg)
+	default:
+		return h.w.Info(msg)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2021 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build windows
+// +build windows
+
+package log
+
+import "errors"
+
+type syslogHook struct{}
+
+func newSyslogHook(cfg SyslogConfig) (*syslogHook, error) {
+	return nil, errors.New("log: syslog sink is not supported on windows")
+}
+
+func (h *syslogHook) level() Level             { return LevelInfo }
+func (h *syslogHook) fire(Level, string) error { return nil }